@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
 
 	"github.com/fatih/color"
@@ -23,6 +24,16 @@ var (
 	version string
 
 	dryRun bool
+	update bool
+	check  bool
+	output string
+
+	changed bool
+	staged  bool
+	tracked bool
+	base    string
+
+	jobs int
 )
 
 func NewRootCommand() *cobra.Command {
@@ -39,23 +50,37 @@ func NewRootCommand() *cobra.Command {
 				return fmt.Errorf("error: path %s does not exist", args[0])
 			}
 
-			vt := arguments.ViewHuman
+			vt, err := arguments.ParseViewType(output)
+			if err != nil {
+				return err
+			}
 			v := view.NewRenderer(vt, &view.View{
 				Stream: &view.Stream{
 					Writer: os.Stdout,
 				},
 			})
 
-			v.Render(color.BlueString(fmt.Sprintf("Using \"%s\"\n", file)))
-			v.Render("\n")
-			v.Render(color.BlueString(fmt.Sprintf("Processing \"%s\"\n", args[0])))
+			if vt == arguments.ViewHuman {
+				v.Render(color.BlueString(fmt.Sprintf("Using \"%s\"\n", file)))
+				v.Render("\n")
+				v.Render(color.BlueString(fmt.Sprintf("Processing \"%s\"\n", args[0])))
+			}
 
 			cfg, err := config.Unmarshal(file)
 			if err != nil {
 				return err
 			}
 
-			hp := header.NewProcessor(cfg, args[0], v, dryRun)
+			hp := header.NewProcessor(cfg, args[0], v, header.Options{
+				DryRun:  dryRun,
+				Update:  update,
+				Check:   check,
+				Changed: changed,
+				Staged:  staged,
+				Tracked: tracked,
+				Base:    base,
+				Jobs:    jobs,
+			})
 			err = hp.Process()
 			if err != nil {
 				return err
@@ -66,6 +91,14 @@ func NewRootCommand() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "dry run")
+	cmd.Flags().BoolVar(&update, "update", false, "rewrite out-of-date copygen-managed headers in place")
+	cmd.Flags().BoolVar(&check, "check", false, "exit non-zero if any file's header is missing or out of date, without writing")
+	cmd.Flags().StringVar(&output, "output", "human", "output format (human, json)")
+	cmd.Flags().BoolVar(&changed, "changed", false, "only process files changed relative to --base (default HEAD)")
+	cmd.Flags().BoolVar(&staged, "staged", false, "only process files staged in the git index")
+	cmd.Flags().BoolVar(&tracked, "tracked", false, "only process git-tracked files")
+	cmd.Flags().StringVar(&base, "base", "HEAD", "git ref to diff against when used with --changed")
+	cmd.Flags().IntVar(&jobs, "jobs", runtime.NumCPU(), "number of files to process concurrently")
 
 	return cmd
 }
@@ -94,5 +127,6 @@ func Execute() {
 		} else {
 			fmt.Fprintln(os.Stderr, err)
 		}
+		os.Exit(1)
 	}
 }
@@ -3,6 +3,8 @@
 
 package arguments
 
+import "fmt"
+
 // ViewType represents which view layer to use.
 type ViewType rune
 
@@ -24,3 +26,15 @@ func (vt ViewType) String() string {
 		return "unknown"
 	}
 }
+
+// ParseViewType parses the --output flag value into a ViewType.
+func ParseViewType(s string) (ViewType, error) {
+	switch s {
+	case "human":
+		return ViewHuman, nil
+	case "json":
+		return ViewJSON, nil
+	default:
+		return ViewNone, fmt.Errorf("unknown output format %q", s)
+	}
+}
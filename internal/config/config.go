@@ -7,8 +7,25 @@ import (
 )
 
 type Config struct {
+	// Header is executed as a Go text/template with {{.Year}}, {{.YearRange}},
+	// {{.File}}, {{.Author}}, {{.Holder}} and {{.SPDX}} available. If Header is
+	// empty and License names a builtin license (e.g. "Apache-2.0"), that
+	// license's canonical short header is rendered instead.
 	Header  string   `yaml:"Header"`
 	Exclude []string `yaml:"Exclude"`
+
+	// Extensions maps additional file extensions (e.g. ".proto") to one of the
+	// builtin comment style names ("double-slash", "hash", "dash", "block-c",
+	// "block-xml") so copygen can add headers to file types it doesn't know by default.
+	Extensions map[string]string `yaml:"Extensions"`
+
+	// License names a builtin license (e.g. "Apache-2.0") whose canonical
+	// short header is rendered when Header is empty.
+	License string `yaml:"License"`
+	Holder  string `yaml:"Holder"`
+	Author  string `yaml:"Author"`
+	// Year pins {{.Year}} to a fixed value; if empty, the current year is used.
+	Year string `yaml:"Year"`
 }
 
 // Unmarshal reads a ".copygen.yaml" file and unmarshals it.
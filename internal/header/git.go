@@ -0,0 +1,185 @@
+package header
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gitSelection resolves the Changed/Staged/Tracked options into the set of
+// file paths (relative to dir, slash-separated) that Process should consider.
+// It returns nil if none of those options were set, meaning: walk everything.
+func gitSelection(dir string, opts Options) (map[string]bool, error) {
+	if !opts.Changed && !opts.Staged && !opts.Tracked {
+		return nil, nil
+	}
+
+	selected := make(map[string]bool)
+
+	if opts.Tracked {
+		paths, err := trackedFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			selected[p] = true
+		}
+	}
+
+	if opts.Staged {
+		paths, err := gitDiffNames(dir, "--cached")
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			selected[p] = true
+		}
+	}
+
+	if opts.Changed {
+		base := opts.Base
+		if base == "" {
+			base = "HEAD"
+		}
+		paths, err := gitDiffNames(dir, base)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			selected[p] = true
+		}
+	}
+
+	return selected, nil
+}
+
+// gitDiffNames runs `git diff --name-only --relative --diff-filter=ACMR <ref>` in dir,
+// returning paths relative to dir.
+func gitDiffNames(dir, ref string) ([]string, error) {
+	out, err := runGit(dir, "diff", "--name-only", "--relative", "--diff-filter=ACMR", ref)
+	if err != nil {
+		return nil, err
+	}
+	return splitNonEmptyLines(out), nil
+}
+
+// trackedFiles lists git-tracked files relative to dir, falling back to a
+// small .gitignore matcher when `git ls-files` fails (no git binary, or dir
+// is not inside a repository).
+func trackedFiles(dir string) ([]string, error) {
+	if out, err := runGit(dir, "ls-files"); err == nil {
+		return splitNonEmptyLines(out), nil
+	}
+
+	return walkRespectingGitignore(dir)
+}
+
+// runGit runs git with the given arguments in dir and returns its stdout.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func splitNonEmptyLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// walkRespectingGitignore walks dir, returning paths (relative to dir) for
+// every file not excluded by the root .gitignore. It's a best-effort fallback
+// for --tracked used only when `git ls-files` fails.
+func walkRespectingGitignore(dir string) ([]string, error) {
+	patterns, err := loadGitignore(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if matchesGitignore(patterns, rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.IsDir() {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// loadGitignore reads dir's root .gitignore, if any, into a list of patterns.
+func loadGitignore(dir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	return patterns, scanner.Err()
+}
+
+// matchesGitignore reports whether relPath matches any of the given patterns.
+// It supports plain names, "dir/" suffixes, and glob wildcards, but not
+// negation or the full gitignore pattern syntax.
+func matchesGitignore(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		p := strings.TrimSuffix(pattern, "/")
+
+		if matched, _ := filepath.Match(p, relPath); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(p, filepath.Base(relPath)); matched {
+			return true
+		}
+		if strings.HasPrefix(relPath+"/", p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Copygen. Licensed under the Apache License, Version 2.0.
+// See LICENSE for details. Do not modify this header – changes will be overwritten.
+
+package header
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMatchesGitignore checks plain names, directory patterns, and glob
+// wildcards, the subset of gitignore syntax the fallback matcher supports.
+func TestMatchesGitignore(t *testing.T) {
+	patterns := []string{"*.log", "vendor/", "build"}
+
+	assert.True(t, matchesGitignore(patterns, "debug.log"))
+	assert.True(t, matchesGitignore(patterns, "vendor/pkg/main.go"))
+	assert.True(t, matchesGitignore(patterns, "build"))
+	assert.False(t, matchesGitignore(patterns, "main.go"))
+}
+
+// TestWalkRespectingGitignore checks that the fallback walker used by
+// --tracked when git itself is unavailable honours the root .gitignore.
+func TestWalkRespectingGitignore(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("vendor/\n*.log\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "debug.log"), []byte("noise"), 0o644))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "vendor"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "vendor", "dep.go"), []byte("package dep\n"), 0o644))
+
+	files, err := walkRespectingGitignore(dir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"main.go", ".gitignore"}, files)
+}
+
+// TestGitSelection_noOptions checks that gitSelection returns nil (meaning:
+// walk everything) when none of Changed/Staged/Tracked is set.
+func TestGitSelection_noOptions(t *testing.T) {
+	selection, err := gitSelection(t.TempDir(), Options{})
+	assert.NoError(t, err)
+	assert.Nil(t, selection)
+}
@@ -1,59 +1,454 @@
 package header
 
 import (
-	"bufio"
 	"fmt"
-	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
 
 	"github.com/copygen/copygen/internal/config"
 	"github.com/copygen/copygen/internal/view"
-	"github.com/fatih/color"
 )
 
+// CommentStyle describes how a header is rendered and detected for a family
+// of file types. A line-comment style only sets LinePrefix; a block-comment
+// style sets BlockStart/BlockEnd and, for javadoc-like middle lines, LineContinuation.
+type CommentStyle struct {
+	LinePrefix       string
+	BlockStart       string
+	BlockEnd         string
+	LineContinuation string
+}
+
 var (
-	// commentPrefixes defines the comment style for each file type
-	commentPrefixes = map[string]string{
-		".go": "//",
+	styleDoubleSlash = CommentStyle{LinePrefix: "//"}
+	styleHash        = CommentStyle{LinePrefix: "#"}
+	styleDash        = CommentStyle{LinePrefix: "--"}
+	styleBlockC      = CommentStyle{BlockStart: "/*", BlockEnd: "*/", LineContinuation: " * "}
+	styleBlockXML    = CommentStyle{BlockStart: "<!--", BlockEnd: "-->"}
+
+	// commentStyles defines the comment style for each file type known out of the box.
+	commentStyles = map[string]CommentStyle{
+		".go":    styleDoubleSlash,
+		".js":    styleDoubleSlash,
+		".jsx":   styleDoubleSlash,
+		".ts":    styleDoubleSlash,
+		".tsx":   styleDoubleSlash,
+		".rs":    styleDoubleSlash,
+		".java":  styleDoubleSlash,
+		".c":     styleDoubleSlash,
+		".h":     styleDoubleSlash,
+		".cpp":   styleDoubleSlash,
+		".hpp":   styleDoubleSlash,
+		".cc":    styleDoubleSlash,
+		".swift": styleDoubleSlash,
+		".kt":    styleDoubleSlash,
+		".kts":   styleDoubleSlash,
+
+		".py":   styleHash,
+		".rb":   styleHash,
+		".sh":   styleHash,
+		".bash": styleHash,
+
+		".css": styleBlockC,
+
+		".html": styleBlockXML,
+		".htm":  styleBlockXML,
+		".xml":  styleBlockXML,
+
+		".lua": styleDash,
+		".sql": styleDash,
 	}
+
+	// namedStyles exposes the builtin comment styles by name so users can map
+	// additional extensions onto one of them via Extensions in .copygen.yaml.
+	namedStyles = map[string]CommentStyle{
+		"double-slash": styleDoubleSlash,
+		"hash":         styleHash,
+		"dash":         styleDash,
+		"block-c":      styleBlockC,
+		"block-xml":    styleBlockXML,
+	}
+)
+
+// hasPrelude reports whether line is a shebang or an XML prolog that must stay
+// on byte 0 of the file, with the header inserted right after it instead.
+func hasPrelude(line string) bool {
+	return strings.HasPrefix(line, "#!") || strings.HasPrefix(line, "<?xml")
+}
+
+const (
+	// sentinelBegin and sentinelEnd delimit a copygen-managed header block so a
+	// later run can find and rewrite it in place instead of stacking a new header
+	// on top of a stale one.
+	sentinelBegin = "copygen:begin"
+	sentinelEnd   = "copygen:end"
+)
+
+// headerStatus describes how a file's existing content relates to the header
+// that the current configuration would produce.
+type headerStatus int
+
+const (
+	statusMissing headerStatus = iota
+	statusPresent
+	statusStale
 )
 
+// Options configures how Processor walks and mutates files.
+type Options struct {
+	// DryRun reports what would change without writing any files.
+	DryRun bool
+	// Update rewrites a stale copygen-managed header block in place. Without
+	// it, stale blocks are left untouched and reported as skipped.
+	Update bool
+	// Check reports missing or out-of-date headers without writing, and
+	// causes Process to return an error so callers (e.g. a CI pre-commit
+	// hook) can exit non-zero.
+	Check bool
+
+	// Changed restricts processing to files that differ from Base (default
+	// "HEAD"). Staged restricts it to the git index. Tracked restricts it to
+	// git-tracked files, skipping anything gitignored. They can be combined;
+	// the file set processed is their union.
+	Changed bool
+	Staged  bool
+	Tracked bool
+	Base    string
+
+	// Jobs caps how many files are processed concurrently. Zero (the zero
+	// value) means runtime.NumCPU().
+	Jobs int
+}
+
+// headerCacheKey caches the rendered, comment-wrapped header lines for a given
+// comment style and post-template header text, so files that render to the
+// same text (the common case, since only {{.File}} and git-derived
+// {{.YearRange}} vary per file) don't re-wrap it from scratch.
+type headerCacheKey struct {
+	style CommentStyle
+	text  string
+}
+
 // Processor handles file header operations with thread-safe caching.
 type Processor struct {
-	cfg    *config.Config
-	view   view.Renderer
-	dryRun bool
+	cfg  *config.Config
+	view view.Renderer
+	opts Options
 
 	dir       string
-	cache     map[string][]string
+	styles    map[string]CommentStyle
+	cache     map[headerCacheKey][]string
 	cacheMu   sync.RWMutex
 	exclude   []string
 	excludeMu sync.RWMutex
+
+	// viewMu serializes event emission so concurrent workers don't interleave
+	// writes to the underlying renderer's output stream.
+	viewMu sync.Mutex
+
+	tmplOnce          sync.Once
+	tmpl              *template.Template
+	tmplErr           error
+	tmplUsesYearRange bool
+
+	// yearCache memoizes fileFirstCommitYear per path: checkHeader and
+	// addHeader both need it for the same file, and it's a "git log" fork/exec
+	// each time otherwise. A cached 0 means "looked up, no git info available".
+	yearCache   map[string]int
+	yearCacheMu sync.RWMutex
 }
 
 // NewProcessor creates a new header processor.
-func NewProcessor(cfg *config.Config, dir string, v view.Renderer, dryRun bool) *Processor {
+func NewProcessor(cfg *config.Config, dir string, v view.Renderer, opts Options) *Processor {
+	styles := make(map[string]CommentStyle, len(commentStyles)+len(cfg.Extensions))
+	for ext, style := range commentStyles {
+		styles[ext] = style
+	}
+	for ext, name := range cfg.Extensions {
+		if style, ok := namedStyles[name]; ok {
+			styles[ext] = style
+		}
+	}
+
 	return &Processor{
-		cfg:     cfg,
-		dir:     dir,
-		view:    v,
-		dryRun:  dryRun,
-		cache:   make(map[string][]string),
-		exclude: cfg.Exclude,
+		cfg:       cfg,
+		dir:       dir,
+		view:      v,
+		opts:      opts,
+		styles:    styles,
+		cache:     make(map[headerCacheKey][]string),
+		exclude:   cfg.Exclude,
+		yearCache: make(map[string]int),
+	}
+}
+
+// emit forwards e to the Processor's renderer, serializing calls so workers
+// in the pool don't interleave writes to the same output stream.
+func (p *Processor) emit(e view.Event) {
+	p.viewMu.Lock()
+	defer p.viewMu.Unlock()
+	p.view.RenderEvent(e)
+}
+
+// templateData is the set of variables available to a Header or License template.
+type templateData struct {
+	Year      string
+	YearRange string
+	File      string
+	Author    string
+	Holder    string
+	SPDX      string
+}
+
+// headerTemplate lazily parses the configured Header, or the builtin License
+// template it falls back to, once per Processor.
+func (p *Processor) headerTemplate() (*template.Template, error) {
+	p.tmplOnce.Do(func() {
+		source := p.cfg.Header
+		if source == "" && p.cfg.License != "" {
+			lt, ok := licenseTemplates[p.cfg.License]
+			if !ok {
+				p.tmplErr = fmt.Errorf("unknown license %q", p.cfg.License)
+				return
+			}
+			source = lt
+		}
+		if source == "" {
+			return
+		}
+
+		p.tmpl, p.tmplErr = template.New("header").Parse(source)
+		p.tmplUsesYearRange = strings.Contains(source, ".YearRange")
+	})
+	return p.tmpl, p.tmplErr
+}
+
+// renderHeader executes the header template for path, returning "" if no
+// Header or License is configured.
+func (p *Processor) renderHeader(path string) (string, error) {
+	tmpl, err := p.headerTemplate()
+	if err != nil {
+		return "", err
+	}
+	if tmpl == nil {
+		return "", nil
+	}
+
+	year := p.cfg.Year
+	if year == "" {
+		year = strconv.Itoa(time.Now().Year())
+	}
+
+	yearRange := year
+	if p.tmplUsesYearRange {
+		if first, ok := p.cachedFirstCommitYear(path); ok {
+			if firstStr := strconv.Itoa(first); firstStr != year {
+				yearRange = firstStr + "-" + year
+			}
+		}
 	}
+
+	data := templateData{
+		Year:      year,
+		YearRange: yearRange,
+		File:      filepath.Base(path),
+		Author:    p.cfg.Author,
+		Holder:    p.cfg.Holder,
+		SPDX:      p.cfg.License,
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("execute header template: %v", err)
+	}
+
+	return rendered.String(), nil
+}
+
+// cachedFirstCommitYear memoizes fileFirstCommitYear per path, since
+// checkHeader and addHeader each need it once per file and it's otherwise a
+// "git log" fork/exec every call.
+func (p *Processor) cachedFirstCommitYear(path string) (year int, ok bool) {
+	p.yearCacheMu.RLock()
+	cached, hit := p.yearCache[path]
+	p.yearCacheMu.RUnlock()
+	if hit {
+		return cached, cached != 0
+	}
+
+	year, ok = fileFirstCommitYear(path)
+	stored := 0
+	if ok {
+		stored = year
+	}
+
+	p.yearCacheMu.Lock()
+	p.yearCache[path] = stored
+	p.yearCacheMu.Unlock()
+
+	return year, ok
+}
+
+// fileFirstCommitYear returns the year of path's first commit according to
+// git, or ok=false if that information isn't available (no repo, no git, etc).
+func fileFirstCommitYear(path string) (year int, ok bool) {
+	out, err := exec.Command("git", "log", "--follow", "--format=%ad", "--date=format:%Y", "--", path).Output()
+	if err != nil {
+		return 0, false
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	// git log lists commits newest first; the last line is the first commit.
+	year, err = strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return 0, false
+	}
+
+	return year, true
 }
 
-// Process processes all files in the directory, adding headers where needed.
+// processStats accumulates the counters and errors produced by the worker
+// pool in Process. All fields are guarded by mu since workers update it concurrently.
+type processStats struct {
+	mu                                                   sync.Mutex
+	added, updated, skipped, present, errored, outOfDate int
+	errs                                                 *multierror.Error
+}
+
+func (s *processStats) recordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errored++
+	s.errs = multierror.Append(s.errs, err)
+}
+
+func (s *processStats) record(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f()
+}
+
+// Process processes all files in the directory, adding or updating headers
+// where needed. Files are discovered by a single walk and fanned out to a
+// pool of workers (Options.Jobs, default runtime.NumCPU()) so large trees
+// process in parallel; a bad file is recorded as an error and doesn't stop
+// the rest of the run.
 func (p *Processor) Process() error {
-	// Warm up the cache for small configs
-	if len(p.cfg.Header) < 1024 {
-		p.warmCache()
+	start := time.Now()
+
+	// Resolve the Header/License template once up front: an invalid
+	// config (e.g. a typo'd License name) is a single mistake, not a
+	// per-file one, so it should fail once here instead of being
+	// rediscovered and reported by every worker in the pool.
+	if _, err := p.headerTemplate(); err != nil {
+		return fmt.Errorf("resolve header template: %v", err)
+	}
+
+	selection, err := gitSelection(p.dir, p.opts)
+	if err != nil {
+		return fmt.Errorf("git file selection: %v", err)
+	}
+
+	candidates, err := p.collectCandidates(selection)
+	if err != nil {
+		return fmt.Errorf("walk dir: %v", err)
+	}
+	total := len(candidates)
+
+	jobs := p.opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	paths := make(chan string, jobs)
+	go func() {
+		defer close(paths)
+		for _, path := range candidates {
+			paths <- path
+		}
+	}()
+
+	var stats processStats
+	var processed int64
+	var lastPath atomic.Value
+	lastPath.Store("")
+
+	progressStop := make(chan struct{})
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if n := atomic.LoadInt64(&processed); n > 0 {
+					p.emit(view.Event{Kind: view.EventProgress, Processed: int(n), Total: total, Path: lastPath.Load().(string)})
+				}
+			case <-progressStop:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				p.processFile(path, &stats)
+				atomic.AddInt64(&processed, 1)
+				lastPath.Store(filepath.Clean(path))
+			}
+		}()
+	}
+	wg.Wait()
+
+	close(progressStop)
+	<-progressDone
+
+	p.emit(view.Event{
+		Kind:    view.EventSummary,
+		Added:   stats.added,
+		Updated: stats.updated,
+		Skipped: stats.skipped,
+		Present: stats.present,
+		Errors:  stats.errored,
+		Elapsed: time.Since(start).String(),
+	})
+
+	if stats.errs != nil {
+		return stats.errs.ErrorOrNil()
 	}
 
-	var processed int
+	if p.opts.Check && stats.outOfDate > 0 {
+		return fmt.Errorf("%d file(s) have a missing or out-of-date header; run with --update to fix", stats.outOfDate)
+	}
+
+	return nil
+}
+
+// collectCandidates walks dir once, returning every path whose extension
+// maps to a known comment style and, when selection is non-nil, is also a
+// member of it. Exclusion and header inspection are left to processFile so
+// the walk itself stays cheap.
+func (p *Processor) collectCandidates(selection map[string]bool) ([]string, error) {
+	var candidates []string
 	err := filepath.Walk(p.dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -62,52 +457,222 @@ func (p *Processor) Process() error {
 			return nil
 		}
 
-		ext := filepath.Ext(path)
-		prefix, ok := commentPrefixes[ext]
-		if !ok || p.isExcluded(path) {
+		if _, ok := p.styles[filepath.Ext(path)]; !ok {
 			return nil
 		}
 
-		hasHeader, err := p.checkHeader(path, prefix)
-		if err != nil {
-			return fmt.Errorf("check header: %v", err)
+		if selection != nil {
+			rel, err := filepath.Rel(p.dir, path)
+			if err != nil {
+				return err
+			}
+			if !selection[filepath.ToSlash(rel)] {
+				return nil
+			}
 		}
 
-		if hasHeader {
-			return nil
+		candidates = append(candidates, path)
+		return nil
+	})
+	return candidates, err
+}
+
+// processFile runs the add/update/check logic for a single file, recording
+// its outcome in stats and emitting the corresponding event. It's safe to
+// call concurrently from multiple workers.
+func (p *Processor) processFile(path string, stats *processStats) {
+	style := p.styles[filepath.Ext(path)]
+	cleanPath := filepath.Clean(path)
+
+	if p.isExcluded(path) {
+		stats.record(func() { stats.skipped++ })
+		p.emit(view.Event{Kind: view.EventHeaderSkipped, Path: cleanPath, Reason: "excluded"})
+		return
+	}
+
+	status, err := p.checkHeader(path, style)
+	if err != nil {
+		stats.recordError(fmt.Errorf("check header %s: %v", cleanPath, err))
+		p.emit(view.Event{Kind: view.EventError, Path: cleanPath, Reason: err.Error()})
+		return
+	}
+
+	switch status {
+	case statusPresent:
+		stats.record(func() { stats.present++ })
+		p.emit(view.Event{Kind: view.EventHeaderPresent, Path: cleanPath})
+
+	case statusStale:
+		if p.opts.Check {
+			stats.record(func() { stats.outOfDate++ })
+			p.emit(view.Event{Kind: view.EventHeaderStale, Path: cleanPath, Reason: "header does not match configuration"})
+			return
+		}
+		if !p.opts.Update {
+			stats.record(func() { stats.skipped++ })
+			p.emit(view.Event{Kind: view.EventHeaderSkipped, Path: cleanPath, Reason: "stale header (run with --update to refresh)"})
+			return
+		}
+		if p.opts.DryRun {
+			stats.record(func() { stats.updated++ })
+			p.emit(view.Event{Kind: view.EventHeaderStale, Path: cleanPath, Reason: "would update"})
+			return
+		}
+		if err := p.addHeader(path, style); err != nil {
+			stats.recordError(fmt.Errorf("update header %s: %v", cleanPath, err))
+			p.emit(view.Event{Kind: view.EventError, Path: cleanPath, Reason: err.Error()})
+			return
+		}
+		stats.record(func() { stats.updated++ })
+		p.emit(view.Event{Kind: view.EventHeaderUpdated, Path: cleanPath})
+
+	default: // statusMissing
+		if p.opts.Check {
+			stats.record(func() { stats.outOfDate++ })
+			p.emit(view.Event{Kind: view.EventHeaderWouldAdd, Path: cleanPath})
+			return
 		}
+		if p.opts.DryRun {
+			stats.record(func() { stats.added++ })
+			p.emit(view.Event{Kind: view.EventHeaderWouldAdd, Path: cleanPath})
+			return
+		}
+		if err := p.addHeader(path, style); err != nil {
+			stats.recordError(fmt.Errorf("add header %s: %v", cleanPath, err))
+			p.emit(view.Event{Kind: view.EventError, Path: cleanPath, Reason: err.Error()})
+			return
+		}
+		stats.record(func() { stats.added++ })
+		p.emit(view.Event{Kind: view.EventHeaderAdded, Path: cleanPath})
+	}
+}
 
-		processed++
-		if p.dryRun {
-			p.view.Render(color.BlueString(fmt.Sprintf("Would add header to \"%s\"\n", filepath.Clean(path))))
-			return nil
+// splitLines splits content into lines, each retaining its trailing "\n" (the
+// last line won't have one if the file doesn't end in a newline), so the
+// original slices can be reassembled byte-for-byte around a replaced block.
+func splitLines(content []byte) [][]byte {
+	var lines [][]byte
+	start := 0
+	for i, b := range content {
+		if b == '\n' {
+			lines = append(lines, content[start:i+1])
+			start = i + 1
 		}
+	}
+	if start < len(content) {
+		lines = append(lines, content[start:])
+	}
+	return lines
+}
 
-		if err := p.addHeader(path, prefix); err != nil {
-			return fmt.Errorf("add header: %v", err)
+// trimNL strips the trailing newline from a line produced by splitLines.
+func trimNL(line []byte) string {
+	return strings.TrimRight(string(line), "\n")
+}
+
+// linesMatch reports whether lines[at:] begins with the given content, one
+// entry per line.
+func linesMatch(lines [][]byte, at int, want []string) bool {
+	if at < 0 || at+len(want) > len(lines) {
+		return false
+	}
+	for i, w := range want {
+		if trimNL(lines[at+i]) != w {
+			return false
 		}
+	}
+	return true
+}
 
-		return nil
-	})
+// headerInspection locates a file's existing copygen header block, if any,
+// as a half-open range of line indices, so addHeader can splice the desired
+// header into its place.
+type headerInspection struct {
+	status     headerStatus
+	blockStart int
+	blockEnd   int
+}
 
-	if err != nil {
-		return fmt.Errorf("walk dir: %v", err)
+// inspectHeader compares a file's lines against the header the current
+// configuration would produce, recognizing a previously-inserted block by
+// its copygen:begin/copygen:end sentinel comments even if its contents differ.
+func (p *Processor) inspectHeader(lines [][]byte, style CommentStyle, rendered string) headerInspection {
+	header := p.getHeader(style, rendered)
+
+	insertAt := 0
+	if len(lines) > 0 && hasPrelude(trimNL(lines[0])) {
+		insertAt = 1
 	}
 
-	return nil
+	if linesMatch(lines, insertAt, header) {
+		return headerInspection{status: statusPresent, blockStart: insertAt, blockEnd: insertAt + len(header)}
+	}
+
+	beginLine := formatLine(style, sentinelBegin)
+	endLine := formatLine(style, sentinelEnd)
+
+	// A managed block can only start where a header would be inserted;
+	// matching the sentinel text anywhere else in the file (a doc comment
+	// quoting copygen's own syntax, a test fixture, a README snippet) must
+	// not be mistaken for one, or addHeader would splice over real content.
+	start := insertAt
+	if style.BlockStart != "" {
+		// In a block-comment header the begin sentinel follows the BlockStart line.
+		if len(lines) <= insertAt || trimNL(lines[insertAt]) != style.BlockStart {
+			return headerInspection{status: statusMissing, blockStart: insertAt, blockEnd: insertAt}
+		}
+		start = insertAt + 1
+	}
+	if len(lines) > start && trimNL(lines[start]) == beginLine {
+		for j := start + 1; j < len(lines); j++ {
+			if trimNL(lines[j]) == endLine {
+				end := j + 1
+				if style.BlockStart != "" {
+					if end >= len(lines) || trimNL(lines[end]) != style.BlockEnd {
+						break
+					}
+					end++
+				}
+				return headerInspection{status: statusStale, blockStart: insertAt, blockEnd: end}
+			}
+		}
+	}
+
+	return headerInspection{status: statusMissing, blockStart: insertAt, blockEnd: insertAt}
 }
 
-// addHeader writes the header to a file using atomic replacement.
-func (p *Processor) addHeader(path, prefix string) error {
-	header := p.getHeader(prefix)
-	headerBytes := []byte(strings.Join(header, "\n") + "\n\n")
+// addHeader inserts a new header, or in update mode rewrites a stale one, using
+// atomic replacement.
+func (p *Processor) addHeader(path string, style CommentStyle) error {
+	rendered, err := p.renderHeader(path)
+	if err != nil {
+		return err
+	}
 
-	// Open original file
-	f, err := os.Open(path)
+	content, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+
+	lines := splitLines(content)
+	insp := p.inspectHeader(lines, style, rendered)
+
+	header := p.getHeader(style, rendered)
+	headerBytes := []byte(strings.Join(header, "\n") + "\n\n")
+
+	var out []byte
+	for _, l := range lines[:insp.blockStart] {
+		out = append(out, l...)
+	}
+	out = append(out, headerBytes...)
+
+	rest := lines[insp.blockEnd:]
+	if insp.status == statusStale && len(rest) > 0 && trimNL(rest[0]) == "" {
+		rest = rest[1:] // drop the blank separator line that followed the old block
+	}
+	for _, l := range rest {
+		out = append(out, l...)
+	}
 
 	// Create temp file in same directory
 	tmp, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
@@ -124,18 +689,12 @@ func (p *Processor) addHeader(path, prefix string) error {
 		}
 	}()
 
-	// Write header first
-	if _, err := tmp.Write(headerBytes); err != nil {
-		return err
-	}
-
-	// Copy original content
-	if _, err := io.Copy(tmp, f); err != nil {
+	if _, err := tmp.Write(out); err != nil {
 		return err
 	}
 
 	// Preserve original permissions
-	if stat, err := f.Stat(); err == nil {
+	if stat, err := os.Stat(path); err == nil {
 		tmp.Chmod(stat.Mode())
 	}
 
@@ -150,14 +709,16 @@ func (p *Processor) addHeader(path, prefix string) error {
 		return err
 	}
 
-	p.view.Render(color.BlueString(fmt.Sprintf("Added header to \"%s\"\n", filepath.Clean(path))))
 	return nil
 }
 
-// getHeader returns the cached or generated header.
-func (p *Processor) getHeader(prefix string) []string {
+// getHeader returns the cached or generated, comment-wrapped header lines for
+// style and a file's already-rendered header text.
+func (p *Processor) getHeader(style CommentStyle, rendered string) []string {
+	key := headerCacheKey{style: style, text: rendered}
+
 	p.cacheMu.RLock()
-	cached, ok := p.cache[prefix]
+	cached, ok := p.cache[key]
 	p.cacheMu.RUnlock()
 
 	if ok {
@@ -168,12 +729,12 @@ func (p *Processor) getHeader(prefix string) []string {
 	defer p.cacheMu.Unlock()
 
 	// Check again in case another goroutine updated it
-	if cached, ok := p.cache[prefix]; ok {
+	if cached, ok := p.cache[key]; ok {
 		return cached
 	}
 
-	header := p.buildHeader(prefix)
-	p.cache[prefix] = header
+	header := p.buildHeader(style, rendered)
+	p.cache[key] = header
 	return header
 }
 
@@ -204,65 +765,99 @@ func (p *Processor) isExcluded(path string) bool {
 	return false
 }
 
-// checkHeader verifies if file has the expected header.
-func (p *Processor) checkHeader(path, prefix string) (bool, error) {
-	if p.cfg.Header == "" {
-		return true, nil
+// checkHeader reports whether path's header is present, stale, or missing for style.
+func (p *Processor) checkHeader(path string, style CommentStyle) (headerStatus, error) {
+	rendered, err := p.renderHeader(path)
+	if err != nil {
+		return statusMissing, err
+	}
+	if rendered == "" {
+		return statusPresent, nil
 	}
 
-	header := p.getHeader(prefix)
+	header := p.getHeader(style, rendered)
 	if len(header) == 0 {
-		return true, nil
+		return statusPresent, nil
 	}
 
-	f, err := os.Open(path)
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return false, err
+		return statusMissing, err
 	}
-	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
-	for _, line := range header {
-		if !scanner.Scan() {
-			return false, scanner.Err()
+	return p.inspectHeader(splitLines(content), style, rendered).status, nil
+}
+
+// formatLine renders a single content line using the comment style's line
+// prefix or mid-block continuation marker.
+func formatLine(style CommentStyle, line string) string {
+	switch {
+	case style.LineContinuation != "":
+		if line == "" {
+			return strings.TrimRight(style.LineContinuation, " ")
 		}
-		if scanner.Text() != line {
-			return false, nil
+		return style.LineContinuation + line
+	case style.LinePrefix != "":
+		if line == "" {
+			return strings.TrimRight(style.LinePrefix, " ")
 		}
+		return strings.TrimRight(style.LinePrefix, " ") + " " + line
+	default:
+		return line
 	}
+}
 
-	return true, nil
+// buildHeader creates the formatted header lines for the given comment style
+// and a file's already-rendered header text, wrapped in the copygen:begin/
+// copygen:end sentinel comments that let a later run recognize and update the
+// block in place.
+func (p *Processor) buildHeader(style CommentStyle, rendered string) []string {
+	lines := strings.Split(rendered, "\n")
+	wrapped := make([]string, 0, len(lines)+2)
+	wrapped = append(wrapped, sentinelBegin)
+	wrapped = append(wrapped, lines...)
+	wrapped = append(wrapped, sentinelEnd)
+
+	if style.BlockStart != "" {
+		return buildBlockHeader(style, wrapped)
+	}
+	return buildLineHeader(style, wrapped)
 }
 
-// buildHeader creates the formatted header lines.
-func (p *Processor) buildHeader(prefix string) []string {
-	lines := strings.Split(p.cfg.Header, "\n")
+// buildLineHeader renders lines prefixed with a line-comment marker, e.g. "// ...".
+func buildLineHeader(style CommentStyle, lines []string) []string {
+	bare := strings.TrimRight(style.LinePrefix, " ")
 	var header []string
 
 	for _, line := range lines {
-		if line == "" {
-			header = append(header, prefix)
-		} else {
-			header = append(header, prefix+" "+line)
-		}
+		header = append(header, formatLine(style, line))
 	}
 
 	// Trim trailing empty comments
-	for len(header) > 0 && header[len(header)-1] == prefix {
+	for len(header) > 0 && header[len(header)-1] == bare {
 		header = header[:len(header)-1]
 	}
 
 	return header
 }
 
-// warmCache pre-generates headers for known file types.
-func (p *Processor) warmCache() {
-	p.cacheMu.Lock()
-	defer p.cacheMu.Unlock()
+// buildBlockHeader renders lines wrapped in a block comment, e.g. "/* ... */",
+// using LineContinuation (such as " * ") to prefix the lines in between.
+func buildBlockHeader(style CommentStyle, lines []string) []string {
+	cont := strings.TrimRight(style.LineContinuation, " ")
+	header := []string{style.BlockStart}
 
-	for _, prefix := range commentPrefixes {
-		if _, exists := p.cache[prefix]; !exists {
-			p.cache[prefix] = p.buildHeader(prefix)
+	for _, line := range lines {
+		header = append(header, formatLine(style, line))
+	}
+
+	if style.LineContinuation != "" {
+		// Trim trailing empty continuation lines
+		for len(header) > 1 && header[len(header)-1] == cont {
+			header = header[:len(header)-1]
 		}
 	}
+
+	header = append(header, style.BlockEnd)
+	return header
 }
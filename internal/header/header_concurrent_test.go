@@ -0,0 +1,53 @@
+// Copyright (c) Copygen. Licensed under the Apache License, Version 2.0.
+// See LICENSE for details. Do not modify this header – changes will be overwritten.
+
+package header
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/copygen/copygen/internal/config"
+	"github.com/copygen/copygen/internal/view"
+)
+
+// TestProcess_concurrentWorkers checks that Process adds a header to every
+// candidate file when run with a worker pool (Jobs > 1), and that the shared
+// header cache and stats survive concurrent access intact.
+func TestProcess_concurrentWorkers(t *testing.T) {
+	dir := t.TempDir()
+	const n = 50
+	for i := 0; i < n; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+		assert.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o644))
+	}
+
+	cfg := &config.Config{Header: "Copyright 2026 Acme"}
+	p := NewProcessor(cfg, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{Jobs: 8})
+
+	assert.NoError(t, p.Process())
+
+	for i := 0; i < n; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "// Copyright 2026 Acme")
+	}
+}
+
+// TestProcessStats_recordError checks that recordError both increments the
+// error count and aggregates the error via go-multierror.
+func TestProcessStats_recordError(t *testing.T) {
+	var stats processStats
+	stats.recordError(fmt.Errorf("boom"))
+	stats.recordError(fmt.Errorf("bang"))
+
+	assert.Equal(t, 2, stats.errored)
+	assert.Error(t, stats.errs.ErrorOrNil())
+	assert.Contains(t, stats.errs.Error(), "boom")
+	assert.Contains(t, stats.errs.Error(), "bang")
+}
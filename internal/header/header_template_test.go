@@ -0,0 +1,92 @@
+// Copyright (c) Copygen. Licensed under the Apache License, Version 2.0.
+// See LICENSE for details. Do not modify this header – changes will be overwritten.
+
+package header
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/copygen/copygen/internal/config"
+	"github.com/copygen/copygen/internal/view"
+)
+
+// TestRenderHeader_templateVariables checks that a user-supplied Header
+// template is executed with {{.Year}}, {{.File}}, {{.Author}} and {{.Holder}}.
+func TestRenderHeader_templateVariables(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	assert.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o644))
+
+	cfg := &config.Config{
+		Header: "{{.File}}: Copyright {{.Year}} {{.Holder}}, by {{.Author}}",
+		Year:   "2026",
+		Holder: "Acme",
+		Author: "Jane Doe",
+	}
+	p := NewProcessor(cfg, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{})
+
+	rendered, err := p.renderHeader(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "main.go: Copyright 2026 Acme, by Jane Doe", rendered)
+}
+
+// TestRenderHeader_licenseFallback checks that a builtin License is rendered
+// when Header is empty, and that Header takes precedence when both are set.
+func TestRenderHeader_licenseFallback(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	assert.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o644))
+
+	cfg := &config.Config{License: "MIT", Holder: "Acme", Year: "2026"}
+	p := NewProcessor(cfg, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{})
+
+	rendered, err := p.renderHeader(path)
+	assert.NoError(t, err)
+	assert.Contains(t, rendered, "Copyright (c) 2026 Acme")
+
+	cfg.Header = "Custom header"
+	p2 := NewProcessor(cfg, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{})
+	rendered, err = p2.renderHeader(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "Custom header", rendered)
+}
+
+// TestHeaderTemplate_tracksYearRangeUsage checks that tmplUsesYearRange only
+// turns on when the configured template actually references {{.YearRange}},
+// since computing it shells out to git.
+func TestHeaderTemplate_tracksYearRangeUsage(t *testing.T) {
+	dir := t.TempDir()
+
+	p := NewProcessor(&config.Config{Header: "Copyright {{.Year}}"}, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{})
+	_, err := p.headerTemplate()
+	assert.NoError(t, err)
+	assert.False(t, p.tmplUsesYearRange)
+
+	p2 := NewProcessor(&config.Config{License: "Apache-2.0"}, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{})
+	_, err = p2.headerTemplate()
+	assert.NoError(t, err)
+	assert.True(t, p2.tmplUsesYearRange)
+}
+
+// TestProcess_invalidLicenseFailsOnceUpFront checks that an unknown License
+// name is rejected once before Process fans out to the worker pool, rather
+// than being rediscovered and reported once per candidate file.
+func TestProcess_invalidLicenseFailsOnceUpFront(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("package main\n"), 0o644))
+	}
+
+	cfg := &config.Config{License: "Apache-2.5"}
+	p := NewProcessor(cfg, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{})
+
+	err := p.Process()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown license "Apache-2.5"`)
+	assert.Equal(t, 1, strings.Count(err.Error(), "unknown license"))
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Copygen. Licensed under the Apache License, Version 2.0.
+// See LICENSE for details. Do not modify this header – changes will be overwritten.
+
+package header
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/copygen/copygen/internal/config"
+	"github.com/copygen/copygen/internal/view"
+)
+
+func newTestProcessor(t *testing.T) *Processor {
+	t.Helper()
+	return NewProcessor(&config.Config{}, t.TempDir(), view.NewHumanRenderer(view.NewView(io.Discard)), Options{})
+}
+
+// TestBuildHeader_lineComment checks that a line-comment style (e.g. Go's "//")
+// wraps the rendered header and sentinel markers one line at a time.
+func TestBuildHeader_lineComment(t *testing.T) {
+	p := newTestProcessor(t)
+
+	got := p.buildHeader(styleDoubleSlash, "Copyright 2026 Acme")
+
+	assert.Equal(t, []string{
+		"// copygen:begin",
+		"// Copyright 2026 Acme",
+		"// copygen:end",
+	}, got)
+}
+
+// TestBuildHeader_blockComment checks that a block-comment style (e.g. CSS's
+// "/* ... */") wraps the rendered header using LineContinuation for the
+// middle lines.
+func TestBuildHeader_blockComment(t *testing.T) {
+	p := newTestProcessor(t)
+
+	got := p.buildHeader(styleBlockC, "Copyright 2026 Acme")
+
+	assert.Equal(t, []string{
+		"/*",
+		" * copygen:begin",
+		" * Copyright 2026 Acme",
+		" * copygen:end",
+		"*/",
+	}, got)
+}
+
+// TestHasPrelude checks that shebangs and XML prologs are recognized so the
+// header gets inserted after them instead of at byte 0.
+func TestHasPrelude(t *testing.T) {
+	assert.True(t, hasPrelude("#!/usr/bin/env bash"))
+	assert.True(t, hasPrelude(`<?xml version="1.0"?>`))
+	assert.False(t, hasPrelude("package header"))
+}
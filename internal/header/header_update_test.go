@@ -0,0 +1,75 @@
+// Copyright (c) Copygen. Licensed under the Apache License, Version 2.0.
+// See LICENSE for details. Do not modify this header – changes will be overwritten.
+
+package header
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/copygen/copygen/internal/config"
+	"github.com/copygen/copygen/internal/view"
+)
+
+// TestAddHeader_updatesStaleBlock checks that a previously-inserted
+// copygen:begin/copygen:end block is rewritten in place when the configured
+// header changes, rather than stacked on top of the stale one.
+func TestAddHeader_updatesStaleBlock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	assert.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o644))
+
+	cfg := &config.Config{Header: "Copyright 2020 Acme"}
+	p := NewProcessor(cfg, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{})
+	assert.NoError(t, p.addHeader(path, styleDoubleSlash))
+
+	cfg.Header = "Copyright 2026 Acme"
+	p2 := NewProcessor(cfg, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{Update: true})
+
+	status, err := p2.checkHeader(path, styleDoubleSlash)
+	assert.NoError(t, err)
+	assert.Equal(t, statusStale, status)
+
+	assert.NoError(t, p2.addHeader(path, styleDoubleSlash))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "// copygen:begin\n// Copyright 2026 Acme\n// copygen:end\n\npackage main\n", string(content))
+
+	status, err = p2.checkHeader(path, styleDoubleSlash)
+	assert.NoError(t, err)
+	assert.Equal(t, statusPresent, status)
+}
+
+// TestInspectHeader_ignoresSentinelTextElsewhereInFile checks that a sentinel
+// match isn't recognized as a managed block unless it starts where a header
+// would be inserted, so a doc comment that happens to quote copygen's own
+// sentinel syntax isn't mistaken for a stale header and overwritten.
+func TestInspectHeader_ignoresSentinelTextElsewhereInFile(t *testing.T) {
+	content := "package main\n\n" +
+		"// This file documents copygen's sentinel format:\n" +
+		"// copygen:begin\n" +
+		"// some text that looks like a header but isn't one\n" +
+		"// copygen:end\n\n" +
+		"func main() {}\n"
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	cfg := &config.Config{Header: "Copyright 2026 Acme"}
+	p := NewProcessor(cfg, dir, view.NewHumanRenderer(view.NewView(os.Stdout)), Options{Update: true})
+
+	status, err := p.checkHeader(path, styleDoubleSlash)
+	assert.NoError(t, err)
+	assert.Equal(t, statusMissing, status)
+
+	assert.NoError(t, p.addHeader(path, styleDoubleSlash))
+
+	got, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(got), "// some text that looks like a header but isn't one")
+}
@@ -0,0 +1,71 @@
+package header
+
+// licenseTemplates holds the canonical short header for each builtin license,
+// selectable via the License field in .copygen.yaml. Each entry is itself a
+// Go text/template, rendered with the same variables as a user-supplied Header.
+var licenseTemplates = map[string]string{
+	"Apache-2.0": `Copyright {{.YearRange}} {{.Holder}}
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.`,
+
+	"MIT": `Copyright (c) {{.YearRange}} {{.Holder}}
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.`,
+
+	"BSD-3-Clause": `Copyright (c) {{.YearRange}}, {{.Holder}}
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice,
+   this list of conditions and the following disclaimer.
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation
+   and/or other materials provided with the distribution.
+3. Neither the name of {{.Holder}} nor the names of its contributors may be
+   used to endorse or promote products derived from this software without
+   specific prior written permission.`,
+
+	"MPL-2.0": `Copyright {{.YearRange}} {{.Holder}}
+
+This Source Code Form is subject to the terms of the Mozilla Public
+License, v. 2.0. If a copy of the MPL was not distributed with this
+file, You can obtain one at http://mozilla.org/MPL/2.0/.`,
+
+	"GPL-3.0": `Copyright (C) {{.YearRange}} {{.Holder}}
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.`,
+
+	"ISC": `Copyright (c) {{.YearRange}}, {{.Holder}}
+
+Permission to use, copy, modify, and/or distribute this software for any
+purpose with or without fee is hereby granted, provided that the above
+copyright notice and this permission notice appear in all copies.`,
+}
@@ -0,0 +1,41 @@
+// Copyright (c) Copygen. Licensed under the Apache License, Version 2.0.
+// See LICENSE for details. Do not modify this header – changes will be overwritten.
+
+package view
+
+// EventKind identifies the kind of occurrence an Event describes.
+type EventKind string
+
+const (
+	EventHeaderAdded    EventKind = "header_added"
+	EventHeaderWouldAdd EventKind = "header_would_add"
+	EventHeaderUpdated  EventKind = "header_updated"
+	EventHeaderStale    EventKind = "header_stale"
+	EventHeaderSkipped  EventKind = "header_skipped"
+	EventHeaderPresent  EventKind = "header_present"
+	EventError          EventKind = "error"
+	EventProgress       EventKind = "progress"
+	EventSummary        EventKind = "summary"
+)
+
+// Event is a single, renderer-agnostic occurrence reported by header.Processor.
+// A Renderer turns it into colored text (HumanRenderer) or a JSON line (JSONRenderer).
+type Event struct {
+	Kind EventKind `json:"kind"`
+	Path string    `json:"path,omitempty"`
+	// Reason carries extra context for header_skipped, header_stale and error events.
+	Reason string `json:"reason,omitempty"`
+
+	// Processed and Total are only populated on a progress event, reporting
+	// how many of the candidate files the worker pool has processed so far.
+	Processed int `json:"processed,omitempty"`
+	Total     int `json:"total,omitempty"`
+
+	// The fields below are only populated on a summary event.
+	Added   int    `json:"added,omitempty"`
+	Updated int    `json:"updated,omitempty"`
+	Skipped int    `json:"skipped,omitempty"`
+	Present int    `json:"present,omitempty"`
+	Errors  int    `json:"errors,omitempty"`
+	Elapsed string `json:"elapsed,omitempty"`
+}
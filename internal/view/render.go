@@ -4,18 +4,25 @@
 package view
 
 import (
-	"github.com/bschaatsbergen/copygen/internal/arguments"
+	"fmt"
+	"strings"
+
+	"github.com/copygen/copygen/internal/arguments"
+	"github.com/fatih/color"
 )
 
-// Renderer interface with a unified Render method.
+// Renderer renders free-form text as well as the typed events emitted by header.Processor.
 type Renderer interface {
 	Render(input string)
+	RenderEvent(e Event)
 }
 
 func NewRenderer(vt arguments.ViewType, view *View) Renderer {
 	switch vt {
 	case arguments.ViewHuman:
-		return &HumanRenderer{view}
+		return &HumanRenderer{view: view}
+	case arguments.ViewJSON:
+		return &JSONRenderer{view}
 	default:
 		panic("unknown view type")
 	}
@@ -24,6 +31,11 @@ func NewRenderer(vt arguments.ViewType, view *View) Renderer {
 // HumanRenderer for writing human-readable output.
 type HumanRenderer struct {
 	view *View
+
+	// lastProgressLen is the length of the last EventProgress line written,
+	// so the next one can pad over any leftover characters from a longer
+	// line, and any non-progress event knows to start on a fresh line.
+	lastProgressLen int
 }
 
 // Validate that HumanRenderer implements the Renderer interface.
@@ -42,3 +54,41 @@ func (v *HumanRenderer) Render(input string) {
 		panic(err)
 	}
 }
+
+// RenderEvent prints a colored, human-readable line for the given event. A
+// progress line is overwritten in place; any other event first breaks onto a
+// fresh line so it doesn't get appended to a dangling progress line.
+func (v *HumanRenderer) RenderEvent(e Event) {
+	if e.Kind != EventProgress && v.lastProgressLen > 0 {
+		v.Render("\n")
+		v.lastProgressLen = 0
+	}
+
+	switch e.Kind {
+	case EventHeaderAdded:
+		v.Render(color.BlueString(fmt.Sprintf("Added header to \"%s\"\n", e.Path)))
+	case EventHeaderWouldAdd:
+		v.Render(color.BlueString(fmt.Sprintf("Would add header to \"%s\"\n", e.Path)))
+	case EventHeaderUpdated:
+		v.Render(color.BlueString(fmt.Sprintf("Updated header in \"%s\"\n", e.Path)))
+	case EventHeaderStale:
+		v.Render(color.YellowString(fmt.Sprintf("Header out of date in \"%s\": %s\n", e.Path, e.Reason)))
+	case EventHeaderSkipped:
+		v.Render(color.YellowString(fmt.Sprintf("Skipped \"%s\": %s\n", e.Path, e.Reason)))
+	case EventHeaderPresent:
+		// Already up to date; the human renderer stays quiet to avoid noise.
+	case EventError:
+		v.Render(color.RedString(fmt.Sprintf("Error processing \"%s\": %s\n", e.Path, e.Reason)))
+	case EventProgress:
+		line := fmt.Sprintf("[%d/%d] %s", e.Processed, e.Total, e.Path)
+		out := line
+		if pad := v.lastProgressLen - len(line); pad > 0 {
+			out += strings.Repeat(" ", pad)
+		}
+		v.Render("\r" + out)
+		v.lastProgressLen = len(line)
+	case EventSummary:
+		v.Render(color.BlueString(fmt.Sprintf("Processed: %d added, %d updated, %d skipped, %d present, %d errors (%s)\n",
+			e.Added, e.Updated, e.Skipped, e.Present, e.Errors, e.Elapsed)))
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Copygen. Licensed under the Apache License, Version 2.0.
+// See LICENSE for details. Do not modify this header – changes will be overwritten.
+
+package view
+
+import "encoding/json"
+
+// JSONRenderer writes one JSON object per line (NDJSON), one per Event, so
+// CI can consume copygen output by grepping for e.g. `"kind":"error"` or by
+// computing metrics from the final summary event.
+type JSONRenderer struct {
+	view *View
+}
+
+// Validate that JSONRenderer implements the Renderer interface.
+var _ Renderer = (*JSONRenderer)(nil)
+
+// NewJSONRenderer creates a JSONRenderer with a "json" view bound to an output stream.
+func NewJSONRenderer(view *View) *JSONRenderer {
+	return &JSONRenderer{
+		view: view,
+	}
+}
+
+// Render discards free-form text; emitting it would break the NDJSON stream.
+func (v *JSONRenderer) Render(input string) {}
+
+// RenderEvent marshals the event and writes it as a single NDJSON line.
+func (v *JSONRenderer) RenderEvent(e Event) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err := v.view.Stream.Writer.Write(append(b, '\n')); err != nil {
+		panic(err)
+	}
+}
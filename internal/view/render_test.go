@@ -7,7 +7,7 @@ import (
 	"bytes"
 	"testing"
 
-	"github.com/bschaatsbergen/copygen/internal/arguments"
+	"github.com/copygen/copygen/internal/arguments"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,3 +26,29 @@ func TestNewRenderer_human(t *testing.T) {
 	// Check that the view's stream writer is the same as the buffer
 	assert.Equal(t, &b, humanRenderer.view.Stream.Writer)
 }
+
+// TestNewRenderer_json tests the NewRenderer function, which should return a JSONRenderer
+// and bind provided io.Writer to the view's stream writer.
+func TestNewRenderer_json(t *testing.T) {
+	b := bytes.Buffer{}
+	jv := NewRenderer(arguments.ViewJSON, NewView(&b))
+
+	// Check that the view is a JSONRenderer
+	jsonRenderer, ok := jv.(*JSONRenderer)
+	assert.True(t, ok, "Expected jv to be of type *JSONRenderer")
+
+	assert.IsType(t, &JSONRenderer{}, jsonRenderer)
+
+	// Check that the view's stream writer is the same as the buffer
+	assert.Equal(t, &b, jsonRenderer.view.Stream.Writer)
+}
+
+// TestJSONRenderer_RenderEvent tests that RenderEvent writes a single NDJSON line per event.
+func TestJSONRenderer_RenderEvent(t *testing.T) {
+	b := bytes.Buffer{}
+	jv := NewJSONRenderer(NewView(&b))
+
+	jv.RenderEvent(Event{Kind: EventHeaderAdded, Path: "main.go"})
+
+	assert.Equal(t, `{"kind":"header_added","path":"main.go"}`+"\n", b.String())
+}